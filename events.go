@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+
+	"github.com/acooks/pymfcd/pkg/mfcevents"
+)
+
+// runEvents implements the "events" subcommand: it subscribes to the
+// kernel's multicast routing upcalls and prints each one as it arrives,
+// until interrupted.
+func runEvents(args []string) {
+	fs := flag.NewFlagSet("events", flag.ExitOnError)
+	fs.Parse(args)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	events, err := mfcevents.Subscribe(ctx)
+	if err != nil {
+		log.Fatalf("Failed to subscribe to MFC events: %v. Try running with sudo.", err)
+	}
+
+	fmt.Println("Listening for MFC cache-miss and forwarding events (Ctrl-C to stop)...")
+	for event := range events {
+		fmt.Printf("[%s] vif=%d origin=%s group=%s", event.Kind, event.Vif, event.Origin, event.Mcastgrp)
+		if event.Packet != nil {
+			fmt.Printf(" packet_bytes=%d", len(event.Packet))
+		}
+		fmt.Println()
+	}
+}
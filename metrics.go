@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricsServer exposes the current MFC snapshot as Prometheus gauges on
+// /metrics and as a plain JSON document on /json.
+type metricsServer struct {
+	store *snapshotStore
+	mux   *http.ServeMux
+
+	packets prometheus.GaugeVec
+	bytes   prometheus.GaugeVec
+	wrongIf prometheus.GaugeVec
+	pps     prometheus.GaugeVec
+	bps     prometheus.GaugeVec
+}
+
+func newMetricsServer(store *snapshotStore) *metricsServer {
+	labels := []string{"origin", "mcastgrp", "parent"}
+	s := &metricsServer{
+		store:   store,
+		packets: *prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "mfc_packets", Help: "Packets forwarded for this MFC entry, as last reported by the kernel."}, labels),
+		bytes:   *prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "mfc_bytes", Help: "Bytes forwarded for this MFC entry, as last reported by the kernel."}, labels),
+		wrongIf: *prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "mfc_wrong_if", Help: "Packets received on the wrong interface for this MFC entry."}, labels),
+		pps:     *prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "mfc_packets_per_second", Help: "Packets/sec forwarded for this MFC entry since the previous poll."}, labels),
+		bps:     *prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "mfc_bytes_per_second", Help: "Bytes/sec forwarded for this MFC entry since the previous poll."}, labels),
+	}
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(&s.packets, &s.bytes, &s.wrongIf, &s.pps, &s.bps)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	mux.HandleFunc("/json", s.handleJSON)
+	s.mux = mux
+
+	return s
+}
+
+// ListenAndServe refreshes the gauge vectors from the snapshot store on
+// every scrape and serves HTTP until err or the process exits.
+func (s *metricsServer) ListenAndServe(addr string) error {
+	srv := &http.Server{
+		Addr: addr,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s.refresh()
+			s.mux.ServeHTTP(w, r)
+		}),
+	}
+	return srv.ListenAndServe()
+}
+
+func (s *metricsServer) refresh() {
+	// Reset before repopulating so flows that have left the MFC since
+	// the last scrape stop exporting their last-known value forever.
+	s.packets.Reset()
+	s.bytes.Reset()
+	s.wrongIf.Reset()
+	s.pps.Reset()
+	s.bps.Reset()
+
+	flows := s.store.snapshot()
+	for key, flow := range flows {
+		labels := prometheus.Labels{
+			"origin":   key.Origin,
+			"mcastgrp": key.Mcastgrp,
+			"parent":   fmt.Sprintf("%d", key.Parent),
+		}
+		s.packets.With(labels).Set(float64(flow.Entry.Packets))
+		s.bytes.With(labels).Set(float64(flow.Entry.Bytes))
+		s.wrongIf.With(labels).Set(float64(flow.WrongIfTotal))
+		s.pps.With(labels).Set(flow.PacketsRate)
+		s.bps.With(labels).Set(flow.BytesRate)
+	}
+}
+
+// jsonFlow is the wire format served on /json, one entry per active flow.
+type jsonFlow struct {
+	Origin      string  `json:"origin"`
+	Mcastgrp    string  `json:"mcastgrp"`
+	Parent      int     `json:"parent"`
+	Packets     uint64  `json:"packets"`
+	Bytes       uint64  `json:"bytes"`
+	WrongIf     uint64  `json:"wrong_if"`
+	PacketsRate float64 `json:"packets_per_sec"`
+	BytesRate   float64 `json:"bytes_per_sec"`
+}
+
+func (s *metricsServer) handleJSON(w http.ResponseWriter, r *http.Request) {
+	flows := s.store.snapshot()
+	out := make([]jsonFlow, 0, len(flows))
+	for key, flow := range flows {
+		out = append(out, jsonFlow{
+			Origin:      key.Origin,
+			Mcastgrp:    key.Mcastgrp,
+			Parent:      key.Parent,
+			Packets:     flow.Entry.Packets,
+			Bytes:       flow.Entry.Bytes,
+			WrongIf:     flow.WrongIfTotal,
+			PacketsRate: flow.PacketsRate,
+			BytesRate:   flow.BytesRate,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(out); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
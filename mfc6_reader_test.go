@@ -0,0 +1,78 @@
+package main
+
+import (
+	"net"
+	"os"
+	"testing"
+)
+
+func TestParseMfc6_Basic(t *testing.T) {
+	f, err := os.Open("testdata/ip6_mr_cache_basic")
+	if err != nil {
+		t.Fatalf("opening fixture: %v", err)
+	}
+	defer f.Close()
+
+	entries, err := parseMfc6(f)
+	if err != nil {
+		t.Fatalf("parseMfc6: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+
+	want0 := Mfc6Entry{
+		MfcOrigin:   mustParseIP(t, "2001:db8::1"),
+		MfcMcastgrp: mustParseIP(t, "ff3e:3c::dead"),
+		MfcParent:   2,
+		Packets:     10,
+		Bytes:       1040,
+		WrongIf:     0,
+	}
+	if entries[0].MfcParent != want0.MfcParent || entries[0].Packets != want0.Packets ||
+		entries[0].Bytes != want0.Bytes || entries[0].WrongIf != want0.WrongIf {
+		t.Fatalf("entry 0 mismatch: got %+v", entries[0])
+	}
+	if !entries[0].MfcOrigin.Equal(want0.MfcOrigin) {
+		t.Fatalf("entry 0 origin mismatch: got %s want %s", entries[0].MfcOrigin, want0.MfcOrigin)
+	}
+	if !entries[0].MfcMcastgrp.Equal(want0.MfcMcastgrp) {
+		t.Fatalf("entry 0 group mismatch: got %s want %s", entries[0].MfcMcastgrp, want0.MfcMcastgrp)
+	}
+
+	if entries[1].WrongIf != 1 {
+		t.Fatalf("entry 1 expected WrongIf=1, got %d", entries[1].WrongIf)
+	}
+}
+
+func TestParseMfc6_LargeCounters(t *testing.T) {
+	f, err := os.Open("testdata/ip6_mr_cache_large_counters")
+	if err != nil {
+		t.Fatalf("opening fixture: %v", err)
+	}
+	defer f.Close()
+
+	entries, err := parseMfc6(f)
+	if err != nil {
+		t.Fatalf("parseMfc6: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+
+	if entries[0].Packets != 4294967296 {
+		t.Fatalf("expected Packets to survive 64-bit width, got %d", entries[0].Packets)
+	}
+	if entries[0].Bytes != 9223372036854775807 {
+		t.Fatalf("expected Bytes to survive 64-bit width, got %d", entries[0].Bytes)
+	}
+}
+
+func mustParseIP(t *testing.T, s string) net.IP {
+	t.Helper()
+	ip := net.ParseIP(s)
+	if ip == nil {
+		t.Fatalf("invalid test IP literal %q", s)
+	}
+	return ip
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// mrtDelMfc is MRT_DEL_MFC from linux/mroute.h.
+const mrtDelMfc = 205
+
+// mfcctlLen is sizeof(struct mfcctl) from linux/mroute.h on a 4-byte
+// aligned platform:
+//
+//	struct mfcctl {
+//	    struct in_addr mfcc_origin, mfcc_mcastgrp;
+//	    vifi_t         mfcc_parent;
+//	    unsigned char  mfcc_ttls[MAXVIFS]; // MAXVIFS == 32
+//	    unsigned int   mfcc_pkt_cnt, mfcc_byte_cnt, mfcc_wrong_if;
+//	    int            mfcc_expire;
+//	};
+const mfcctlLen = 60
+
+// MfcDel evicts a single MFC entry from the kernel via the MRT_DEL_MFC
+// setsockopt on an AF_INET/IPPROTO_IGMP socket, the same control-plane
+// primitive mrouted/pimd/smcroute use to manage the cache.
+func MfcDel(entry netlink.MfcEntry) error {
+	sock, err := unix.Socket(unix.AF_INET, unix.SOCK_RAW, unix.IPPROTO_IGMP)
+	if err != nil {
+		return fmt.Errorf("opening IGMP raw socket: %w", err)
+	}
+	defer unix.Close(sock)
+
+	ctl, err := newMfcctl(entry.MfcOrigin, entry.MfcMcastgrp, entry.MfcParent)
+	if err != nil {
+		return err
+	}
+
+	if err := unix.SetsockoptString(sock, unix.IPPROTO_IP, mrtDelMfc, string(ctl)); err != nil {
+		return fmt.Errorf("MRT_DEL_MFC: %w", err)
+	}
+	return nil
+}
+
+// newMfcctl encodes struct mfcctl, populating only the fields MRT_DEL_MFC
+// inspects (mfcc_origin, mfcc_mcastgrp, mfcc_parent); the counters, oif
+// list and expiry are ignored by the kernel on delete and left zeroed.
+func newMfcctl(origin, mcastgrp net.IP, parent int) ([]byte, error) {
+	origin4 := origin.To4()
+	mcastgrp4 := mcastgrp.To4()
+	if origin4 == nil || mcastgrp4 == nil {
+		return nil, fmt.Errorf("MfcDel only supports IPv4 entries, got origin=%s mcastgrp=%s", origin, mcastgrp)
+	}
+
+	buf := make([]byte, mfcctlLen)
+	copy(buf[0:4], origin4)
+	copy(buf[4:8], mcastgrp4)
+	binary.LittleEndian.PutUint16(buf[8:10], uint16(parent))
+	// buf[10:42] is mfcc_ttls[MAXVIFS], buf[42:44] is the compiler's
+	// alignment padding before the trailing u32 fields; both stay zero.
+	return buf, nil
+}
@@ -1,21 +1,132 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
+	"os"
+	"time"
 
 	"github.com/vishvananda/netlink"
+
+	"github.com/acooks/pymfcd/pkg/mfcreaper"
 )
 
 func main() {
-	// MfcGet retrieves the multicast forwarding cache from the kernel.
-	// This requires sufficient privileges to access netlink sockets.
-	mfcStats, err := netlink.MfcGet()
+	if len(os.Args) > 1 && os.Args[1] == "events" {
+		runEvents(os.Args[2:])
+		return
+	}
+
+	interval := flag.Duration("interval", 0, "poll the MFC on this interval and run as a daemon (e.g. 5s); if zero, print a single snapshot and exit")
+	listen := flag.String("listen", ":9090", "address to serve /metrics and /json on when running in daemon mode")
+	reapStaleAfter := flag.Duration("reap-stale-after", 0, "evict MFC entries whose packet counter hasn't advanced for this long; if zero, the reaper is disabled")
+	reapDryRun := flag.Bool("reap-dry-run", false, "with -reap-stale-after, only log eviction candidates instead of evicting them")
+	family := flag.String("family", "4", "address family to report: 4, 6, or both")
+	table := flag.String("table", "253", "multicast routing table id to report, or \"all\" to probe table ids 0-255 (RT_TABLE_DEFAULT is 253)")
+	flag.Parse()
+
+	if *interval > 0 {
+		runDaemon(*interval, *listen, *reapStaleAfter, *reapDryRun)
+		return
+	}
+
+	var rows []unifiedMfcRow
+
+	if *family == "4" || *family == "both" {
+		tables, err := resolveTables(*table)
+		if err != nil {
+			log.Fatalf("Failed to get MFC stats: %v. Try running with sudo.", err)
+		}
+		for _, t := range tables {
+			for _, entry := range t.Entries {
+				rows = append(rows, unifiedRowFrom4(t.Table, entry))
+			}
+		}
+	}
+
+	if *family == "6" || *family == "both" {
+		mfc6Stats, err := MfcGet6()
+		if err != nil {
+			log.Fatalf("Failed to get MFC6 stats: %v. Try running with sudo.", err)
+		}
+		for _, entry := range mfc6Stats {
+			rows = append(rows, unifiedRowFrom6(defaultTableID, entry))
+		}
+	}
+
+	printMfcTable(rows)
+}
+
+// defaultTableID is RT_TABLE_DEFAULT, the table the kernel uses when no
+// multicast VRF / policy routing is configured.
+const defaultTableID = 253
+
+// resolveTables turns the -table flag value into the concrete set of
+// tables, with entries already fetched, to print. "all" probes table ids
+// 0 through 255 once via MfcListTables; a specific id is fetched with a single
+// MfcGetTable call.
+func resolveTables(table string) ([]TableMfc, error) {
+	if table == "all" {
+		return MfcListTables()
+	}
+
+	var id uint32
+	if _, err := fmt.Sscanf(table, "%d", &id); err != nil {
+		return nil, fmt.Errorf("parsing table id %q: %w", table, err)
+	}
+
+	entries, err := MfcGetTable(id)
 	if err != nil {
-		log.Fatalf("Failed to get MFC stats: %v. Try running with sudo.", err)
+		return nil, err
+	}
+	return []TableMfc{{Table: id, Entries: entries}}, nil
+}
+
+// unifiedMfcRow is a family-agnostic view of one MFC entry, used to print
+// v4 and v6 entries in a single table.
+type unifiedMfcRow struct {
+	Family   int
+	Table    uint32
+	Origin   fmt.Stringer
+	Mcastgrp fmt.Stringer
+	Parent   int
+	Packets  uint64
+	Bytes    uint64
+	WrongIf  uint64
+}
+
+func unifiedRowFrom4(table uint32, entry netlink.MfcEntry) unifiedMfcRow {
+	return unifiedMfcRow{
+		Family:   4,
+		Table:    table,
+		Origin:   entry.MfcOrigin,
+		Mcastgrp: entry.MfcMcastgrp,
+		Parent:   entry.MfcParent,
+		Packets:  entry.Packets,
+		Bytes:    entry.Bytes,
+		WrongIf:  uint64(entry.WrongIf),
 	}
+}
 
-	if len(mfcStats) == 0 {
+func unifiedRowFrom6(table uint32, entry Mfc6Entry) unifiedMfcRow {
+	return unifiedMfcRow{
+		Family:   6,
+		Table:    table,
+		Origin:   entry.MfcOrigin,
+		Mcastgrp: entry.MfcMcastgrp,
+		Parent:   entry.MfcParent,
+		Packets:  entry.Packets,
+		Bytes:    entry.Bytes,
+		WrongIf:  entry.WrongIf,
+	}
+}
+
+// printMfcTable prints a one-shot human-readable dump of the MFC, merging
+// v4 and v6 entries and printing the Family of every entry.
+func printMfcTable(rows []unifiedMfcRow) {
+	if len(rows) == 0 {
 		fmt.Println("Multicast Forwarding Cache is empty.")
 		fmt.Println("This may be because multicast routing is not enabled or no multicast traffic is flowing.")
 		return
@@ -24,15 +135,68 @@ func main() {
 	fmt.Println("Kernel Multicast Forwarding Cache (MFC):")
 	fmt.Println("-----------------------------------------")
 
-	// Iterate over the MFC entries and print the details for each.
-	for i, entry := range mfcStats {
+	for i, row := range rows {
 		fmt.Printf("Entry %d:\n", i+1)
-		fmt.Printf("  Source Address:      %s\n", entry.MfcOrigin)
-		fmt.Printf("  Multicast Group:     %s\n", entry.MfcMcastgrp)
-		fmt.Printf("  Parent Iface Index:  %d\n", entry.MfcParent)
-		fmt.Printf("  Packets Forwarded:   %d\n", entry.Packets)
-		fmt.Printf("  Bytes Forwarded:     %d\n", entry.Bytes)
-		fmt.Printf("  Packets on Wrong If: %d\n", entry.WrongIf)
+		fmt.Printf("  Family:              %d\n", row.Family)
+		fmt.Printf("  Table:               %d\n", row.Table)
+		fmt.Printf("  Source Address:      %s\n", row.Origin)
+		fmt.Printf("  Multicast Group:     %s\n", row.Mcastgrp)
+		fmt.Printf("  Parent Iface Index:  %d\n", row.Parent)
+		fmt.Printf("  Packets Forwarded:   %d\n", row.Packets)
+		fmt.Printf("  Bytes Forwarded:     %d\n", row.Bytes)
+		fmt.Printf("  Packets on Wrong If: %d\n", row.WrongIf)
 		fmt.Println("-----------------------------------------")
 	}
 }
+
+// runDaemon polls the MFC on the given interval, keeps the latest snapshot
+// and derived rates in the snapshot store, and serves them over HTTP until
+// the process is killed.
+func runDaemon(interval time.Duration, listen string, reapStaleAfter time.Duration, reapDryRun bool) {
+	store := newSnapshotStore()
+
+	poll := func() {
+		mfcStats, err := netlink.MfcGet()
+		if err != nil {
+			log.Printf("Failed to get MFC stats: %v", err)
+			return
+		}
+		store.update(mfcStats, time.Now())
+	}
+
+	poll()
+
+	srv := newMetricsServer(store)
+	go func() {
+		log.Printf("Serving /metrics and /json on %s", listen)
+		if err := srv.ListenAndServe(listen); err != nil {
+			log.Fatalf("Metrics server failed: %v", err)
+		}
+	}()
+
+	if reapStaleAfter > 0 {
+		reaper := mfcreaper.NewReaper(mfcLister{}, MfcDel, mfcreaper.Config{
+			SampleInterval: interval,
+			StaleAfter:     reapStaleAfter,
+			DryRun:         reapDryRun,
+		})
+		go func() {
+			if err := reaper.Run(context.Background()); err != nil {
+				log.Printf("Reaper stopped: %v", err)
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		poll()
+	}
+}
+
+// mfcLister adapts netlink.MfcGet to the mfcreaper.MfcLister interface.
+type mfcLister struct{}
+
+func (mfcLister) MfcList() ([]netlink.MfcEntry, error) {
+	return netlink.MfcGet()
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/vishvananda/netlink"
+	"golang.org/x/sys/unix"
+)
+
+// Linux multicast routing uses the same RTM_GETROUTE dump iproute2 issues
+// for `ip mroute show table N`, filtered to the RTNL_FAMILY_IPMR pseudo
+// family and a specific routing table. These constants aren't exposed by
+// golang.org/x/sys/unix, so they're defined here from
+// linux/rtnetlink.h and linux/fib_rules.h.
+const (
+	rtnlFamilyIPMR = 128
+
+	rtaTable     = 15 // RTA_TABLE
+	rtaMfcStats  = 17 // RTA_MFC_STATS
+	rtaSrcOffset = unix.RTA_SRC
+	rtaDstOffset = unix.RTA_DST
+
+	// solNetlink and netlinkGetStrictChk aren't exposed by
+	// golang.org/x/sys/unix either. Without NETLINK_GET_STRICT_CHK set
+	// on the socket, ipmr_rtm_dumproute never validates the rtmsg body,
+	// so the RTA_TABLE attribute below is silently ignored and every
+	// dump returns all tables merged together.
+	solNetlink          = 270
+	netlinkGetStrictChk = 12
+)
+
+// rtaMfcStatsMsg mirrors struct rta_mfc_stats from linux/rtnetlink.h.
+type rtaMfcStatsMsg struct {
+	Packets uint64
+	Bytes   uint64
+	WrongIf uint64
+}
+
+// MfcGetTable fetches the IPv4 MFC contents of a single multicast routing
+// table, identified by its MRT_TABLE id (see `ip mroute show table N`).
+func MfcGetTable(id uint32) ([]netlink.MfcEntry, error) {
+	sock, err := unix.Socket(unix.AF_NETLINK, unix.SOCK_RAW|unix.SOCK_CLOEXEC, unix.NETLINK_ROUTE)
+	if err != nil {
+		return nil, fmt.Errorf("opening netlink socket: %w", err)
+	}
+	defer unix.Close(sock)
+
+	if err := unix.Bind(sock, &unix.SockaddrNetlink{Family: unix.AF_NETLINK}); err != nil {
+		return nil, fmt.Errorf("binding netlink socket: %w", err)
+	}
+
+	if err := unix.SetsockoptInt(sock, solNetlink, netlinkGetStrictChk, 1); err != nil {
+		return nil, fmt.Errorf("enabling NETLINK_GET_STRICT_CHK: %w", err)
+	}
+
+	req := newRouteTableDumpRequest(rtnlFamilyIPMR, id)
+	if err := unix.Sendto(sock, req, 0, &unix.SockaddrNetlink{Family: unix.AF_NETLINK}); err != nil {
+		return nil, fmt.Errorf("sending route dump request: %w", err)
+	}
+
+	return receiveMfcTableDump(sock)
+}
+
+// TableMfc is the MFC contents of a single multicast routing table, as
+// returned by MfcListTables so callers don't have to re-probe a table
+// they were just handed the entries for.
+type TableMfc struct {
+	Table   uint32
+	Entries []netlink.MfcEntry
+}
+
+// MfcListTables probes the MRT_TABLE ids 0 through 255 and returns the
+// entries of those with at least one MFC entry; tables with larger ids are
+// not probed and not listed. The kernel has no single "list active
+// tables" call, so this is the same approach `ip mroute show table all`
+// effectively takes: enumerate and skip empty tables.
+func MfcListTables() ([]TableMfc, error) {
+	var active []TableMfc
+
+	// RT_TABLE_DEFAULT (253) is where the kernel keeps the MFC when no
+	// multicast VRF/policy routing is configured, so it must be included
+	// alongside the VRF-assigned ids used by mrouted/pimd/FRR.
+	for id := uint32(0); id <= 255; id++ {
+		entries, err := MfcGetTable(id)
+		if err != nil {
+			return nil, fmt.Errorf("probing table %d: %w", id, err)
+		}
+		if len(entries) > 0 {
+			active = append(active, TableMfc{Table: id, Entries: entries})
+		}
+	}
+
+	return active, nil
+}
+
+// newRouteTableDumpRequest builds an RTM_GETROUTE dump request for the
+// given pseudo-family, scoped to a single routing table via RTA_TABLE.
+// rtm_table is also set directly (when it fits in a byte) since strict
+// dump validation checks the rtmsg body, not just the attribute.
+func newRouteTableDumpRequest(family uint8, table uint32) []byte {
+	const rtmsgLen = 12
+
+	nlmsgLen := unix.NLMSG_HDRLEN + rtmsgLen
+	tableAttr := newRtattr(rtaTable, uint32ToBytes(table))
+	nlmsgLen += len(tableAttr)
+
+	buf := make([]byte, nlmsgLen)
+	binary.LittleEndian.PutUint32(buf[0:4], uint32(nlmsgLen))
+	binary.LittleEndian.PutUint16(buf[4:6], unix.RTM_GETROUTE)
+	binary.LittleEndian.PutUint16(buf[6:8], unix.NLM_F_REQUEST|unix.NLM_F_ROOT|unix.NLM_F_MATCH)
+	// Sequence number and port id are left zero; this is a one-shot
+	// request/response exchange, not a sustained netlink session.
+
+	rtmsg := buf[unix.NLMSG_HDRLEN:]
+	rtmsg[0] = family // rtm_family
+	const rtmTableOffset = 4
+	if table <= 0xff {
+		rtmsg[rtmTableOffset] = byte(table)
+	}
+	copy(rtmsg[rtmsgLen:], tableAttr)
+
+	return buf
+}
+
+// newRtattr encodes a single netlink attribute (rtattr header + payload,
+// padded to a 4-byte boundary).
+func newRtattr(attrType uint16, data []byte) []byte {
+	length := 4 + len(data)
+	padded := (length + 3) &^ 3
+
+	buf := make([]byte, padded)
+	binary.LittleEndian.PutUint16(buf[0:2], uint16(length))
+	binary.LittleEndian.PutUint16(buf[2:4], attrType)
+	copy(buf[4:], data)
+	return buf
+}
+
+func uint32ToBytes(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.LittleEndian.PutUint32(b, v)
+	return b
+}
+
+// receiveMfcTableDump reads the RTM_NEWROUTE dump response and decodes
+// origin/group/iif/counters out of each message's attributes.
+func receiveMfcTableDump(sock int) ([]netlink.MfcEntry, error) {
+	var entries []netlink.MfcEntry
+
+	buf := make([]byte, 65536)
+	for {
+		n, err := recvNetlinkDatagram(sock, &buf)
+		if err != nil {
+			return nil, err
+		}
+
+		msgs, err := unix.ParseNetlinkMessage(buf[:n])
+		if err != nil {
+			return nil, fmt.Errorf("parsing netlink messages: %w", err)
+		}
+
+		done := false
+		for _, msg := range msgs {
+			switch msg.Header.Type {
+			case unix.NLMSG_DONE:
+				done = true
+			case unix.NLMSG_ERROR:
+				return nil, fmt.Errorf("netlink error response")
+			case unix.RTM_NEWROUTE:
+				entry, err := decodeMfcRouteMsg(msg.Data)
+				if err != nil {
+					return nil, err
+				}
+				entries = append(entries, entry)
+			}
+		}
+		if done {
+			break
+		}
+	}
+
+	return entries, nil
+}
+
+// recvNetlinkDatagram reads one full netlink dump datagram into *buf,
+// growing it first if the kernel's message is larger than the buffer
+// currently on hand. Dump datagrams are sized by the kernel independent
+// of the reader, so a fixed-size buffer risks silently truncating large
+// MFC dumps; MSG_PEEK|MSG_TRUNC reports the real size without consuming
+// the datagram, so the buffer can be grown before the real read.
+func recvNetlinkDatagram(sock int, buf *[]byte) (int, error) {
+	peekLen, _, err := unix.Recvfrom(sock, *buf, unix.MSG_PEEK|unix.MSG_TRUNC)
+	if err != nil {
+		return 0, fmt.Errorf("peeking netlink datagram size: %w", err)
+	}
+	if peekLen > len(*buf) {
+		*buf = make([]byte, peekLen)
+	}
+
+	n, _, err := unix.Recvfrom(sock, *buf, 0)
+	if err != nil {
+		return 0, fmt.Errorf("reading netlink dump: %w", err)
+	}
+	return n, nil
+}
+
+// decodeMfcRouteMsg parses the rtmsg body of a single RTM_NEWROUTE message
+// into an MfcEntry.
+func decodeMfcRouteMsg(data []byte) (netlink.MfcEntry, error) {
+	const rtmsgLen = 12
+	if len(data) < rtmsgLen {
+		return netlink.MfcEntry{}, fmt.Errorf("short rtmsg: %d bytes", len(data))
+	}
+
+	attrs, err := unix.ParseNetlinkRouteAttr(&unix.NetlinkMessage{
+		Header: unix.NlMsghdr{Len: uint32(unix.NLMSG_HDRLEN + len(data)), Type: unix.RTM_NEWROUTE},
+		Data:   data,
+	})
+	if err != nil {
+		return netlink.MfcEntry{}, fmt.Errorf("parsing rtattrs: %w", err)
+	}
+
+	var entry netlink.MfcEntry
+	for _, attr := range attrs {
+		switch int(attr.Attr.Type) {
+		case rtaSrcOffset:
+			entry.MfcOrigin = append([]byte(nil), attr.Value...)
+		case rtaDstOffset:
+			entry.MfcMcastgrp = append([]byte(nil), attr.Value...)
+		case unix.RTA_IIF:
+			entry.MfcParent = int(binary.LittleEndian.Uint32(attr.Value))
+		case rtaMfcStats:
+			if len(attr.Value) >= 24 {
+				entry.Packets = binary.LittleEndian.Uint64(attr.Value[0:8])
+				entry.Bytes = binary.LittleEndian.Uint64(attr.Value[8:16])
+				entry.WrongIf = int(binary.LittleEndian.Uint64(attr.Value[16:24]))
+			}
+		}
+	}
+
+	return entry, nil
+}
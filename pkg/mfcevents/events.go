@@ -0,0 +1,52 @@
+// Package mfcevents delivers the kernel's multicast routing upcalls
+// (IGMPMSG_NOCACHE, IGMPMSG_WRONGVIF, IGMPMSG_WHOLEPKT) as a Go channel.
+// These are the cache-miss and forwarding-exception notifications a
+// userspace multicast routing daemon (mrouted, pimd, smcroute) uses to
+// learn about new (S,G) traffic and install MFC entries in response.
+package mfcevents
+
+import "net"
+
+// Kind identifies which of the three igmpmsg upcall types an MfcEvent
+// carries, mirroring the IGMPMSG_* constants from linux/mroute.h.
+type Kind int
+
+const (
+	// NoCache is delivered when a packet arrives for a group with no
+	// matching MFC entry yet — the classic cache-miss upcall.
+	NoCache Kind = iota + 1
+	// WrongVif is delivered when a packet arrives on an interface that
+	// isn't the expected incoming interface for its (S,G) entry.
+	WrongVif
+	// WholePkt is delivered for PIM register-encapsulated packets that
+	// need to be forwarded to userspace in full, not just notified.
+	WholePkt
+)
+
+func (k Kind) String() string {
+	switch k {
+	case NoCache:
+		return "IGMPMSG_NOCACHE"
+	case WrongVif:
+		return "IGMPMSG_WRONGVIF"
+	case WholePkt:
+		return "IGMPMSG_WHOLEPKT"
+	default:
+		return "unknown"
+	}
+}
+
+// MfcEvent is one decoded multicast routing upcall.
+type MfcEvent struct {
+	Kind Kind
+	// Vif is the incoming virtual interface index the kernel attached
+	// the upcall to.
+	Vif int
+	// Origin and Mcastgrp are the (S,G) the upcall concerns.
+	Origin   net.IP
+	Mcastgrp net.IP
+	// Packet holds the packet that triggered the upcall, present for
+	// both WholePkt and NoCache events; it is nil for WrongVif, which
+	// carries no payload.
+	Packet []byte
+}
@@ -0,0 +1,122 @@
+package mfcevents
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+// igmpmsgLen is sizeof(struct igmpmsg) from linux/mroute.h:
+//
+//	struct igmpmsg {
+//	    __u32         unused1, unused2;
+//	    unsigned char im_msgtype;
+//	    unsigned char im_mbz;
+//	    unsigned char im_vif;
+//	    unsigned char unused3;
+//	    struct in_addr im_src, im_dst;
+//	};
+const igmpmsgLen = 20
+
+// Subscribe opens an MRT_INIT multicast routing socket and delivers decoded
+// upcalls on the returned channel until ctx is cancelled, at which point the
+// channel is closed and the socket released.
+func Subscribe(ctx context.Context) (<-chan MfcEvent, error) {
+	sock, err := unix.Socket(unix.AF_INET, unix.SOCK_RAW, unix.IPPROTO_IGMP)
+	if err != nil {
+		return nil, fmt.Errorf("opening IGMP raw socket: %w", err)
+	}
+
+	// MRT_INIT (200 on Linux) tells the kernel this socket is the
+	// multicast routing control/upcall socket.
+	const mrtInit = 200
+	if err := unix.SetsockoptInt(sock, unix.IPPROTO_IP, mrtInit, 1); err != nil {
+		unix.Close(sock)
+		return nil, fmt.Errorf("MRT_INIT: %w", err)
+	}
+
+	events := make(chan MfcEvent)
+
+	var closeOnce sync.Once
+	closeSock := func() { closeOnce.Do(func() { unix.Close(sock) }) }
+
+	// Recvfrom below blocks until an upcall arrives, so ctx cancellation
+	// only unblocks it by closing the socket out from under it — waiting
+	// for the next upcall to notice ctx.Done() would leave the socket
+	// and goroutine lingering indefinitely.
+	go func() {
+		<-ctx.Done()
+		closeSock()
+	}()
+
+	go func() {
+		defer close(events)
+		defer closeSock()
+
+		buf := make([]byte, 4096)
+		for {
+			n, _, err := unix.Recvfrom(sock, buf, 0)
+			if err != nil {
+				return
+			}
+
+			event, ok := decodeUpcall(buf[:n])
+			if !ok {
+				continue
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// decodeUpcall extracts an MfcEvent from a raw read off the MRT_INIT
+// socket. The kernel overlays the igmpmsg directly onto the buffer (the
+// mrouted/pimd/smcroute convention), so there is no IP header to skip.
+// Reads that aren't upcalls (im_mbz != 0, i.e. a genuine IGMP packet
+// rather than a kernel notification) are rejected.
+func decodeUpcall(raw []byte) (MfcEvent, bool) {
+	if len(raw) < igmpmsgLen {
+		return MfcEvent{}, false
+	}
+
+	msgType := raw[8]
+	mbz := raw[9]
+	if mbz != 0 {
+		return MfcEvent{}, false
+	}
+
+	var kind Kind
+	switch msgType {
+	case 1:
+		kind = NoCache
+	case 2:
+		kind = WrongVif
+	case 3:
+		kind = WholePkt
+	default:
+		return MfcEvent{}, false
+	}
+
+	event := MfcEvent{
+		Kind:     kind,
+		Vif:      int(raw[10]),
+		Origin:   net.IPv4(raw[12], raw[13], raw[14], raw[15]),
+		Mcastgrp: net.IPv4(raw[16], raw[17], raw[18], raw[19]),
+	}
+
+	if (kind == WholePkt || kind == NoCache) && len(raw) > igmpmsgLen {
+		event.Packet = append([]byte(nil), raw[igmpmsgLen:]...)
+	}
+
+	return event, true
+}
@@ -0,0 +1,83 @@
+package mfcevents
+
+import "testing"
+
+// buildUpcall assembles a fake igmpmsg read, as would arrive on the
+// MRT_INIT socket, for use in decode tests. Field offsets follow struct
+// igmpmsg: unused1/unused2 (8 bytes), im_msgtype, im_mbz, im_vif, unused3,
+// then im_src/im_dst.
+func buildUpcall(msgType, vif byte, src, dst [4]byte, payload []byte) []byte {
+	raw := make([]byte, igmpmsgLen+len(payload))
+	raw[8] = msgType
+	raw[9] = 0 // im_mbz must be zero to mark this as an upcall
+	raw[10] = vif
+	copy(raw[12:16], src[:])
+	copy(raw[16:20], dst[:])
+	copy(raw[igmpmsgLen:], payload)
+	return raw
+}
+
+func TestDecodeUpcall_NoCache(t *testing.T) {
+	raw := buildUpcall(1, 2, [4]byte{10, 0, 0, 1}, [4]byte{239, 1, 1, 1}, nil)
+
+	event, ok := decodeUpcall(raw)
+	if !ok {
+		t.Fatal("expected decode to succeed")
+	}
+	if event.Kind != NoCache {
+		t.Fatalf("expected NoCache, got %v", event.Kind)
+	}
+	if event.Vif != 2 {
+		t.Fatalf("expected vif 2, got %d", event.Vif)
+	}
+	if event.Origin.String() != "10.0.0.1" || event.Mcastgrp.String() != "239.1.1.1" {
+		t.Fatalf("unexpected origin/group: %s/%s", event.Origin, event.Mcastgrp)
+	}
+	if event.Packet != nil {
+		t.Fatal("expected no packet payload when the upcall carried none")
+	}
+}
+
+func TestDecodeUpcall_NoCacheCarriesPayload(t *testing.T) {
+	payload := []byte{0x45, 0x00, 0x00, 0x1c}
+	raw := buildUpcall(1, 2, [4]byte{10, 0, 0, 1}, [4]byte{239, 1, 1, 1}, payload)
+
+	event, ok := decodeUpcall(raw)
+	if !ok {
+		t.Fatal("expected decode to succeed")
+	}
+	if len(event.Packet) != len(payload) || event.Packet[0] != payload[0] {
+		t.Fatalf("expected the triggering packet to be preserved, got %v", event.Packet)
+	}
+}
+
+func TestDecodeUpcall_WholePktCarriesPayload(t *testing.T) {
+	payload := []byte{0xde, 0xad, 0xbe, 0xef}
+	raw := buildUpcall(3, 0, [4]byte{10, 0, 0, 2}, [4]byte{239, 2, 2, 2}, payload)
+
+	event, ok := decodeUpcall(raw)
+	if !ok {
+		t.Fatal("expected decode to succeed")
+	}
+	if event.Kind != WholePkt {
+		t.Fatalf("expected WholePkt, got %v", event.Kind)
+	}
+	if len(event.Packet) != len(payload) || event.Packet[0] != payload[0] {
+		t.Fatalf("expected payload to be preserved, got %v", event.Packet)
+	}
+}
+
+func TestDecodeUpcall_RejectsGenuineIgmpPacket(t *testing.T) {
+	raw := buildUpcall(1, 0, [4]byte{10, 0, 0, 1}, [4]byte{239, 1, 1, 1}, nil)
+	raw[9] = 1 // im_mbz non-zero: a real IGMP packet, not an upcall
+
+	if _, ok := decodeUpcall(raw); ok {
+		t.Fatal("expected a non-zero im_mbz to be rejected")
+	}
+}
+
+func TestDecodeUpcall_RejectsShortRead(t *testing.T) {
+	if _, ok := decodeUpcall(make([]byte, 4)); ok {
+		t.Fatal("expected a short read to be rejected")
+	}
+}
@@ -0,0 +1,148 @@
+// Package mfcreaper watches the kernel multicast forwarding cache over time
+// and evicts entries that have gone stale, i.e. whose packet counter has
+// stopped advancing because the sender has gone silent. Without this, MFC
+// entries accumulate indefinitely.
+package mfcreaper
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/vishvananda/netlink"
+)
+
+// MfcLister is the subset of netlink used to list the MFC, abstracted out
+// so the sampling and eviction logic can be tested without root or a real
+// netlink socket.
+type MfcLister interface {
+	MfcList() ([]netlink.MfcEntry, error)
+}
+
+// Deleter evicts a single MFC entry from the kernel. In production this is
+// the MfcDel helper in the main package, which wraps MRT_DEL_MFC.
+type Deleter func(entry netlink.MfcEntry) error
+
+// Config controls how aggressively the reaper evicts stale entries.
+type Config struct {
+	// SampleInterval is how often the MFC is polled.
+	SampleInterval time.Duration
+	// StaleAfter is how long an entry's Packets counter must remain
+	// unchanged before it is considered stale.
+	StaleAfter time.Duration
+	// DryRun, when true, only logs eviction candidates instead of
+	// calling Delete.
+	DryRun bool
+}
+
+// mfcKey identifies an MFC flow independent of its counters.
+type mfcKey struct {
+	Origin   string
+	Mcastgrp string
+	Parent   int
+}
+
+func keyOf(entry netlink.MfcEntry) mfcKey {
+	return mfcKey{
+		Origin:   entry.MfcOrigin.String(),
+		Mcastgrp: entry.MfcMcastgrp.String(),
+		Parent:   entry.MfcParent,
+	}
+}
+
+// trackedFlow is the reaper's bookkeeping for one flow between samples.
+type trackedFlow struct {
+	entry       netlink.MfcEntry
+	lastChanged time.Time
+}
+
+// Reaper samples the MFC on Config.SampleInterval and evicts entries whose
+// Packets counter hasn't advanced for Config.StaleAfter.
+type Reaper struct {
+	Lister MfcLister
+	Delete Deleter
+	Config Config
+
+	tracked map[mfcKey]trackedFlow
+}
+
+// NewReaper builds a Reaper ready to Run. lister and deleter are required;
+// deleter is ignored in dry-run mode but must still be non-nil.
+func NewReaper(lister MfcLister, deleter Deleter, cfg Config) *Reaper {
+	return &Reaper{
+		Lister:  lister,
+		Delete:  deleter,
+		Config:  cfg,
+		tracked: make(map[mfcKey]trackedFlow),
+	}
+}
+
+// Run samples on Config.SampleInterval until ctx is cancelled, evicting (or,
+// in dry-run mode, logging) stale entries as they're found.
+func (r *Reaper) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.Config.SampleInterval)
+	defer ticker.Stop()
+
+	if err := r.sample(time.Now()); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			if err := r.sample(now); err != nil {
+				log.Printf("mfcreaper: sample failed: %v", err)
+			}
+		}
+	}
+}
+
+// sample takes one snapshot, updates per-flow last-changed timestamps, and
+// evicts anything that has been unchanged for at least StaleAfter.
+func (r *Reaper) sample(now time.Time) error {
+	entries, err := r.Lister.MfcList()
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[mfcKey]bool, len(entries))
+	for _, entry := range entries {
+		key := keyOf(entry)
+		seen[key] = true
+
+		prev, ok := r.tracked[key]
+		if !ok || prev.entry.Packets != entry.Packets {
+			r.tracked[key] = trackedFlow{entry: entry, lastChanged: now}
+			continue
+		}
+
+		if now.Sub(prev.lastChanged) < r.Config.StaleAfter {
+			continue
+		}
+
+		if r.Config.DryRun {
+			log.Printf("mfcreaper: (dry-run) stale MFC entry, would evict: origin=%s group=%s parent=%d packets=%d idle=%s",
+				entry.MfcOrigin, entry.MfcMcastgrp, entry.MfcParent, entry.Packets, now.Sub(prev.lastChanged))
+			continue
+		}
+
+		log.Printf("mfcreaper: evicting stale MFC entry: origin=%s group=%s parent=%d packets=%d idle=%s",
+			entry.MfcOrigin, entry.MfcMcastgrp, entry.MfcParent, entry.Packets, now.Sub(prev.lastChanged))
+		if err := r.Delete(entry); err != nil {
+			log.Printf("mfcreaper: failed to evict entry: %v", err)
+			continue
+		}
+		delete(r.tracked, key)
+	}
+
+	// Drop bookkeeping for flows the kernel has already forgotten.
+	for key := range r.tracked {
+		if !seen[key] {
+			delete(r.tracked, key)
+		}
+	}
+
+	return nil
+}
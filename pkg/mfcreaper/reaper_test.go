@@ -0,0 +1,116 @@
+package mfcreaper
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/vishvananda/netlink"
+)
+
+// fakeLister returns a canned slice of MfcEntry on each call, advancing
+// through a script so tests can simulate counters advancing or stalling.
+type fakeLister struct {
+	snapshots [][]netlink.MfcEntry
+	call      int
+}
+
+func (f *fakeLister) MfcList() ([]netlink.MfcEntry, error) {
+	if f.call >= len(f.snapshots) {
+		f.call = len(f.snapshots) - 1
+	}
+	s := f.snapshots[f.call]
+	f.call++
+	return s, nil
+}
+
+func entry(packets uint64) netlink.MfcEntry {
+	return netlink.MfcEntry{
+		MfcOrigin:   net.ParseIP("10.0.0.1"),
+		MfcMcastgrp: net.ParseIP("239.1.1.1"),
+		MfcParent:   1,
+		Packets:     packets,
+	}
+}
+
+func TestReaper_EvictsStaleEntry(t *testing.T) {
+	lister := &fakeLister{snapshots: [][]netlink.MfcEntry{
+		{entry(100)},
+		{entry(100)}, // unchanged
+		{entry(100)}, // still unchanged, now past StaleAfter
+	}}
+
+	var deleted []netlink.MfcEntry
+	deleter := func(e netlink.MfcEntry) error {
+		deleted = append(deleted, e)
+		return nil
+	}
+
+	r := NewReaper(lister, deleter, Config{StaleAfter: 2 * time.Minute})
+
+	base := time.Now()
+	if err := r.sample(base); err != nil {
+		t.Fatalf("sample 1: %v", err)
+	}
+	if err := r.sample(base.Add(1 * time.Minute)); err != nil {
+		t.Fatalf("sample 2: %v", err)
+	}
+	if len(deleted) != 0 {
+		t.Fatalf("expected no eviction before StaleAfter elapses, got %d", len(deleted))
+	}
+
+	if err := r.sample(base.Add(3 * time.Minute)); err != nil {
+		t.Fatalf("sample 3: %v", err)
+	}
+	if len(deleted) != 1 {
+		t.Fatalf("expected 1 eviction, got %d", len(deleted))
+	}
+}
+
+func TestReaper_DryRunDoesNotDelete(t *testing.T) {
+	lister := &fakeLister{snapshots: [][]netlink.MfcEntry{
+		{entry(100)},
+		{entry(100)},
+	}}
+
+	deleteCalled := false
+	deleter := func(e netlink.MfcEntry) error {
+		deleteCalled = true
+		return nil
+	}
+
+	r := NewReaper(lister, deleter, Config{StaleAfter: time.Minute, DryRun: true})
+
+	base := time.Now()
+	_ = r.sample(base)
+	_ = r.sample(base.Add(2 * time.Minute))
+
+	if deleteCalled {
+		t.Fatal("expected dry-run mode not to call Delete")
+	}
+}
+
+func TestReaper_ResetsCounterWhenPacketsAdvance(t *testing.T) {
+	lister := &fakeLister{snapshots: [][]netlink.MfcEntry{
+		{entry(100)},
+		{entry(150)}, // advanced, resets the idle timer
+		{entry(150)}, // unchanged again, but only one interval since the reset
+	}}
+
+	deleted := 0
+	deleter := func(e netlink.MfcEntry) error {
+		deleted++
+		return nil
+	}
+
+	r := NewReaper(lister, deleter, Config{StaleAfter: 2 * time.Minute})
+
+	base := time.Now()
+	_ = r.sample(base)
+	_ = r.sample(base.Add(3 * time.Minute))
+	_ = r.sample(base.Add(4 * time.Minute))
+
+	if deleted != 0 {
+		t.Fatalf("expected no eviction once the counter had advanced, got %d", deleted)
+	}
+}
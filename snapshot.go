@@ -0,0 +1,88 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/vishvananda/netlink"
+)
+
+// mfcKey identifies a single MFC flow independent of its counters, so that
+// successive snapshots can be diffed per-flow.
+type mfcKey struct {
+	Origin   string
+	Mcastgrp string
+	Parent   int
+}
+
+// mfcFlow is the last-seen counters and derived rates for one flow.
+type mfcFlow struct {
+	Entry        netlink.MfcEntry
+	PacketsRate  float64 // packets/sec since the previous sample
+	BytesRate    float64 // bytes/sec since the previous sample
+	WrongIfTotal uint64
+}
+
+// snapshotStore holds the most recent MFC snapshot plus rates derived from
+// the previous one. It is safe for concurrent use by the poll loop and the
+// HTTP handlers.
+type snapshotStore struct {
+	mu       sync.RWMutex
+	flows    map[mfcKey]mfcFlow
+	lastSeen time.Time
+}
+
+func newSnapshotStore() *snapshotStore {
+	return &snapshotStore{flows: make(map[mfcKey]mfcFlow)}
+}
+
+// update diffs the new snapshot against the previous one and recomputes
+// per-flow rates. Flows that have disappeared from the kernel MFC are
+// dropped.
+func (s *snapshotStore) update(entries []netlink.MfcEntry, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	elapsed := now.Sub(s.lastSeen).Seconds()
+	fresh := make(map[mfcKey]mfcFlow, len(entries))
+
+	for _, entry := range entries {
+		key := mfcKey{
+			Origin:   entry.MfcOrigin.String(),
+			Mcastgrp: entry.MfcMcastgrp.String(),
+			Parent:   entry.MfcParent,
+		}
+
+		flow := mfcFlow{Entry: entry, WrongIfTotal: uint64(entry.WrongIf)}
+		// A lower counter than the previous sample means the kernel
+		// entry was evicted and recreated between polls (e.g. by the
+		// mfcreaper) rather than that it genuinely decreased; treat
+		// that as a reset instead of underflowing the uint64 subtraction
+		// into a bogus rate spike.
+		if prev, ok := s.flows[key]; ok && elapsed > 0 {
+			if entry.Packets >= prev.Entry.Packets {
+				flow.PacketsRate = float64(entry.Packets-prev.Entry.Packets) / elapsed
+			}
+			if entry.Bytes >= prev.Entry.Bytes {
+				flow.BytesRate = float64(entry.Bytes-prev.Entry.Bytes) / elapsed
+			}
+		}
+		fresh[key] = flow
+	}
+
+	s.flows = fresh
+	s.lastSeen = now
+}
+
+// snapshot returns a point-in-time copy of the current flows, safe for the
+// caller to range over without holding the store's lock.
+func (s *snapshotStore) snapshot() map[mfcKey]mfcFlow {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make(map[mfcKey]mfcFlow, len(s.flows))
+	for k, v := range s.flows {
+		out[k] = v
+	}
+	return out
+}